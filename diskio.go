@@ -0,0 +1,257 @@
+//go:build windows
+
+package windowsdiagnostics
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"unsafe"
+
+	sensor "go.viam.com/rdk/components/sensor"
+	"go.viam.com/rdk/logging"
+	"go.viam.com/rdk/resource"
+	"golang.org/x/sys/windows"
+)
+
+const (
+	defaultDiskIOPath = `\\.\PhysicalDrive0`
+
+	// ioctlDiskPerformance is IOCTL_DISK_PERFORMANCE.
+	ioctlDiskPerformance = 0x00070020
+
+	// hundredNsPerSecond converts a 100-ns tick count (as used by
+	// DISK_PERFORMANCE.QueryTime/IdleTime/ReadTime/WriteTime) to seconds.
+	hundredNsPerSecond = 1e7
+)
+
+var DiskIO = resource.NewModel("bill", "windows-diagnostics", "diskio")
+
+func init() {
+	resource.RegisterComponent(sensor.API, DiskIO,
+		resource.Registration[sensor.Sensor, *DiskIOConfig]{
+			Constructor: newWindowsDiagnosticsDiskIO,
+		},
+	)
+}
+
+type DiskIOConfig struct {
+	// Path is the physical device to query, e.g. `\\.\PhysicalDrive0` or
+	// `\\.\C:`.
+	Path string `json:"path"`
+}
+
+// Validate performs validation ONLY.
+// Do NOT mutate config here — mutations are discarded by Viam.
+func (cfg *DiskIOConfig) Validate(path string) ([]string, []string, error) {
+	return nil, nil, nil
+}
+
+// diskPerformance mirrors the Windows DISK_PERFORMANCE struct returned by
+// IOCTL_DISK_PERFORMANCE. alignmentPadding is required so the struct's size
+// matches what the kernel writes back on 32-bit builds.
+type diskPerformance struct {
+	BytesRead           int64
+	BytesWritten        int64
+	ReadTime            int64
+	WriteTime           int64
+	IdleTime            int64
+	ReadCount           uint32
+	WriteCount          uint32
+	QueueDepth          uint32
+	SplitCount          uint32
+	QueryTime           int64
+	StorageDeviceNumber uint32
+	StorageManagerName  [8]uint16
+	alignmentPadding    uint32
+}
+
+type windowsDiagnosticsDiskIO struct {
+	resource.AlwaysRebuild
+
+	name   resource.Name
+	logger logging.Logger
+	cfg    *DiskIOConfig
+
+	cancelCtx  context.Context
+	cancelFunc func()
+
+	mu   sync.Mutex
+	prev *diskPerformance
+}
+
+func newWindowsDiagnosticsDiskIO(
+	ctx context.Context,
+	deps resource.Dependencies,
+	rawConf resource.Config,
+	logger logging.Logger,
+) (sensor.Sensor, error) {
+
+	conf, err := resource.NativeConfig[*DiskIOConfig](rawConf)
+	if err != nil {
+		return nil, err
+	}
+
+	if conf.Path == "" {
+		logger.Debugf("No diskio path configured; defaulting to %q", defaultDiskIOPath)
+		conf.Path = defaultDiskIOPath
+	}
+
+	return NewDiskIO(ctx, deps, rawConf.ResourceName(), conf, logger)
+}
+
+func NewDiskIO(
+	ctx context.Context,
+	deps resource.Dependencies,
+	name resource.Name,
+	conf *DiskIOConfig,
+	logger logging.Logger,
+) (sensor.Sensor, error) {
+
+	cancelCtx, cancelFunc := context.WithCancel(context.Background())
+
+	s := &windowsDiagnosticsDiskIO{
+		name:       name,
+		logger:     logger,
+		cfg:        conf,
+		cancelCtx:  cancelCtx,
+		cancelFunc: cancelFunc,
+	}
+
+	logger.Infof("Windows disk IO diagnostics using path %q", conf.Path)
+
+	return s, nil
+}
+
+func (s *windowsDiagnosticsDiskIO) Name() resource.Name {
+	return s.name
+}
+
+func (s *windowsDiagnosticsDiskIO) Readings(
+	ctx context.Context,
+	extra map[string]interface{},
+) (map[string]interface{}, error) {
+
+	s.logger.Debug("DiskIO Readings called")
+
+	path := s.cfg.Path
+	if path == "" {
+		path = defaultDiskIOPath
+	}
+
+	perf, err := queryDiskPerformance(path, s.logger)
+	if err != nil {
+		return nil, err
+	}
+
+	s.mu.Lock()
+	prev := s.prev
+	s.prev = perf
+	s.mu.Unlock()
+
+	if prev == nil {
+		s.logger.Debug("No previous diskio sample yet; returning cumulative counters only")
+		return map[string]interface{}{
+			"path":         path,
+			"first_sample": true,
+			"read_count":   perf.ReadCount,
+			"write_count":  perf.WriteCount,
+			"queue_depth":  perf.QueueDepth,
+		}, nil
+	}
+
+	elapsed := perf.QueryTime - prev.QueryTime
+	if elapsed <= 0 {
+		return nil, fmt.Errorf("diskio: non-increasing QueryTime for %q", path)
+	}
+	elapsedSeconds := float64(elapsed) / hundredNsPerSecond
+
+	deltaBytesRead := perf.BytesRead - prev.BytesRead
+	deltaBytesWritten := perf.BytesWritten - prev.BytesWritten
+	deltaReadCount := perf.ReadCount - prev.ReadCount
+	deltaWriteCount := perf.WriteCount - prev.WriteCount
+	deltaIdleTime := perf.IdleTime - prev.IdleTime
+	deltaBusyIOTime := (perf.ReadTime - prev.ReadTime) + (perf.WriteTime - prev.WriteTime)
+
+	busyPercent := 0.0
+	if elapsed > 0 {
+		busyPercent = (1 - float64(deltaIdleTime)/float64(elapsed)) * 100
+		if busyPercent < 0 {
+			busyPercent = 0
+		}
+	}
+
+	return map[string]interface{}{
+		"path":                path,
+		"read_bytes_per_sec":  float64(deltaBytesRead) / elapsedSeconds,
+		"write_bytes_per_sec": float64(deltaBytesWritten) / elapsedSeconds,
+		"read_iops":           float64(deltaReadCount) / elapsedSeconds,
+		"write_iops":          float64(deltaWriteCount) / elapsedSeconds,
+		"avg_queue_depth":     float64(deltaBusyIOTime) / float64(elapsed),
+		"busy_percent":        busyPercent,
+	}, nil
+}
+
+func (s *windowsDiagnosticsDiskIO) DoCommand(
+	ctx context.Context,
+	cmd map[string]interface{},
+) (map[string]interface{}, error) {
+	return nil, errUnimplemented
+}
+
+func (s *windowsDiagnosticsDiskIO) Close(context.Context) error {
+	s.cancelFunc()
+	return nil
+}
+
+// queryDiskPerformance opens path and issues IOCTL_DISK_PERFORMANCE,
+// returning the raw DISK_PERFORMANCE counters.
+func queryDiskPerformance(path string, logger logging.Logger) (*diskPerformance, error) {
+	p, err := windows.UTF16PtrFromString(path)
+	if err != nil {
+		return nil, err
+	}
+
+	logger.Debugf("Opening %q for IOCTL_DISK_PERFORMANCE", path)
+
+	handle, err := windows.CreateFile(
+		p,
+		windows.GENERIC_READ,
+		windows.FILE_SHARE_READ|windows.FILE_SHARE_WRITE,
+		nil,
+		windows.OPEN_EXISTING,
+		0,
+		0,
+	)
+	if err != nil {
+		logger.Debugf("CreateFile(%q) failed: %v", path, err)
+		return nil, err
+	}
+	defer windows.CloseHandle(handle)
+
+	var perf diskPerformance
+	var bytesReturned uint32
+
+	err = windows.DeviceIoControl(
+		handle,
+		ioctlDiskPerformance,
+		nil,
+		0,
+		(*byte)(unsafe.Pointer(&perf)),
+		uint32(unsafe.Sizeof(perf)),
+		&bytesReturned,
+		nil,
+	)
+	if err != nil {
+		if err == windows.ERROR_INVALID_FUNCTION {
+			return nil, fmt.Errorf(
+				"IOCTL_DISK_PERFORMANCE not supported on %q; run `diskperf -y` to enable performance counters: %w",
+				path, err,
+			)
+		}
+		logger.Debugf("DeviceIoControl(IOCTL_DISK_PERFORMANCE, %q) failed: %v", path, err)
+		return nil, err
+	}
+
+	return &perf, nil
+}
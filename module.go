@@ -5,6 +5,9 @@ package windowsdiagnostics
 import (
 	"context"
 	"errors"
+	"fmt"
+	"strings"
+	"sync"
 	"syscall"
 
 	sensor "go.viam.com/rdk/components/sensor"
@@ -13,7 +16,18 @@ import (
 	"golang.org/x/sys/windows"
 )
 
-const defaultDiskPath = "C:\\"
+const (
+	defaultDiskPath = "C:\\"
+
+	// defaultWarnUsedPercent and defaultCritUsedPercent are applied when a
+	// Config doesn't set the corresponding threshold.
+	defaultWarnUsedPercent = 80.0
+	defaultCritUsedPercent = 90.0
+
+	// historySize bounds the in-memory ring buffer of past readings kept
+	// for the DoCommand "history" request.
+	historySize = 50
+)
 
 var (
 	Disk             = resource.NewModel("bill", "windows-diagnostics", "disk")
@@ -30,6 +44,32 @@ func init() {
 
 type Config struct {
 	Path string `json:"path"`
+
+	// AllDrives, when true, ignores Path and instead enumerates every
+	// logical drive on the system via GetLogicalDriveStringsW.
+	AllDrives bool `json:"all_drives"`
+
+	// Excludes lists drive letters (e.g. "D:") to skip when AllDrives is
+	// enabled.
+	Excludes []string `json:"excludes"`
+
+	// WarnUsedPercent and CritUsedPercent set the used-space percentage at
+	// which a drive's status becomes "warn" or "critical". Zero means "use
+	// the default (80%/90%)"; set a negative value to disable that
+	// threshold entirely.
+	WarnUsedPercent float64 `json:"warn_used_percent"`
+	CritUsedPercent float64 `json:"crit_used_percent"`
+
+	// MinFreeBytes sets a free-space floor at or below which a drive's
+	// status becomes "critical". Zero disables this threshold.
+	MinFreeBytes uint64 `json:"min_free_bytes"`
+
+	// Smart, when true, additionally queries the physical disk(s) backing
+	// each reported path/drive for SMART predict-failure and device
+	// identity via IOCTL_STORAGE_PREDICT_FAILURE and
+	// IOCTL_STORAGE_QUERY_PROPERTY. Most systems require Administrator for
+	// this.
+	Smart bool `json:"smart"`
 }
 
 // Validate performs validation ONLY.
@@ -47,6 +87,21 @@ type windowsDiagnosticsDisk struct {
 
 	cancelCtx  context.Context
 	cancelFunc func()
+
+	// mu guards thresholds and history, both of which DoCommand can mutate
+	// or read concurrently with Readings.
+	mu         sync.Mutex
+	thresholds thresholds
+	history    []map[string]interface{}
+}
+
+// thresholds holds the runtime-mutable alert thresholds for a drive. It
+// starts from Config but can be overridden in-memory via DoCommand's
+// "set_thresholds", since Viam discards config mutations.
+type thresholds struct {
+	warnUsedPercent float64
+	critUsedPercent float64
+	minFreeBytes    uint64
 }
 
 func newWindowsDiagnosticsDisk(
@@ -80,12 +135,30 @@ func NewDisk(
 
 	cancelCtx, cancelFunc := context.WithCancel(context.Background())
 
+	// Zero means "use the default"; a negative value explicitly disables
+	// that threshold. Applied here, rather than in the RDK-registration
+	// constructor, so every caller of NewDisk (including cmd/cli) gets the
+	// same defaults.
+	warnUsedPercent := conf.WarnUsedPercent
+	if warnUsedPercent == 0 {
+		warnUsedPercent = defaultWarnUsedPercent
+	}
+	critUsedPercent := conf.CritUsedPercent
+	if critUsedPercent == 0 {
+		critUsedPercent = defaultCritUsedPercent
+	}
+
 	s := &windowsDiagnosticsDisk{
 		name:       name,
 		logger:     logger,
 		cfg:        conf,
 		cancelCtx:  cancelCtx,
 		cancelFunc: cancelFunc,
+		thresholds: thresholds{
+			warnUsedPercent: warnUsedPercent,
+			critUsedPercent: critUsedPercent,
+			minFreeBytes:    conf.MinFreeBytes,
+		},
 	}
 
 	logger.Infof("Windows disk diagnostics using path %q", conf.Path)
@@ -103,6 +176,11 @@ func (s *windowsDiagnosticsDisk) Readings(
 ) (map[string]interface{}, error) {
 
 	s.logger.Debug("Disk Readings called")
+
+	if s.cfg.AllDrives {
+		return s.readAllDrives(ctx)
+	}
+
 	s.logger.Debugf("Raw config path: %q", s.cfg.Path)
 
 	// Fallback
@@ -127,21 +205,370 @@ func (s *windowsDiagnosticsDisk) Readings(
 		usedPercent = float64(used) / float64(total) * 100
 	}
 
-	return map[string]interface{}{
+	status, statusReason := evaluateStatus(usedPercent, free, s.getThresholds())
+
+	reading := map[string]interface{}{
 		"path":            path,
 		"total_bytes":     total,
 		"free_bytes":      free,
 		"available_bytes": available,
 		"used_bytes":      used,
 		"used_percent":    usedPercent,
-	}, nil
+		"status":          status,
+		"status_reason":   statusReason,
+	}
+
+	volInfo, err := getVolumeInfo(path, s.logger)
+	if err != nil {
+		s.logger.Debugf("GetVolumeInformation(%q) failed, omitting volume fields: %v", path, err)
+	} else {
+		for k, v := range volInfo {
+			reading[k] = v
+		}
+	}
+
+	if s.cfg.Smart {
+		disks, warnings, err := querySmartInfo(path, s.logger)
+		if err != nil {
+			s.logger.Debugf("SMART query for %q failed: %v", path, err)
+			reading["smart_warning"] = smartWarning(path, err)
+		} else {
+			reading["smart"] = disks
+			if len(warnings) > 0 {
+				reading["smart_warnings"] = warnings
+			}
+		}
+	}
+
+	s.recordHistory(reading)
+
+	return reading, nil
+}
+
+// readAllDrives enumerates every logical drive on the system and reports
+// usage for each. A failure on any single drive (e.g. a CD-ROM with no
+// media, or a disconnected network share) is recorded as a warning rather
+// than aborting the whole reading.
+func (s *windowsDiagnosticsDisk) readAllDrives(
+	ctx context.Context,
+) (map[string]interface{}, error) {
+
+	excluded := make(map[string]bool, len(s.cfg.Excludes))
+	for _, e := range s.cfg.Excludes {
+		excluded[strings.ToUpper(normalizeDiskPath(e))] = true
+	}
+
+	letters, err := listLogicalDrives(s.logger)
+	if err != nil {
+		return nil, err
+	}
+
+	errs := &errorCollector{}
+	drives := map[string]interface{}{}
+	t := s.getThresholds()
+
+	overallStatus := "ok"
+	overallReason := "within configured thresholds"
+
+	for _, letter := range letters {
+		if excluded[strings.ToUpper(letter)] {
+			s.logger.Debugf("Skipping excluded drive %q", letter)
+			continue
+		}
+
+		driveType := driveTypeName(windows.GetDriveType(stringToUTF16Ptr(letter, s.logger, errs)))
+
+		total, free, available, err := getDiskUsage(letter, s.logger)
+		if err != nil {
+			errs.addf("drive %s: %v", letter, err)
+			continue
+		}
+
+		used := total - free
+		usedPercent := 0.0
+		if total > 0 {
+			usedPercent = float64(used) / float64(total) * 100
+		}
+
+		status, statusReason := evaluateStatus(usedPercent, free, t)
+		if statusSeverity(status) > statusSeverity(overallStatus) {
+			overallStatus = status
+			overallReason = fmt.Sprintf("%s: %s", letter, statusReason)
+		}
+
+		drive := map[string]interface{}{
+			"drive_type":      driveType,
+			"total_bytes":     total,
+			"free_bytes":      free,
+			"available_bytes": available,
+			"used_bytes":      used,
+			"used_percent":    usedPercent,
+			"status":          status,
+			"status_reason":   statusReason,
+		}
+
+		volInfo, err := getVolumeInfo(letter, s.logger)
+		if err != nil {
+			s.logger.Debugf("GetVolumeInformation(%q) failed, omitting volume fields: %v", letter, err)
+		} else {
+			for k, v := range volInfo {
+				drive[k] = v
+			}
+		}
+
+		if s.cfg.Smart {
+			disks, warnings, err := querySmartInfo(letter, s.logger)
+			if err != nil {
+				s.logger.Debugf("SMART query for %q failed: %v", letter, err)
+				errs.addf("%s", smartWarning(letter, err))
+			} else {
+				drive["smart"] = disks
+				for _, w := range warnings {
+					errs.addf("%s: %s", letter, w)
+				}
+			}
+		}
+
+		drives[letter] = drive
+	}
+
+	reading := map[string]interface{}{
+		"drives":        drives,
+		"warnings":      errs.warnings,
+		"status":        overallStatus,
+		"status_reason": overallReason,
+	}
+
+	s.recordHistory(reading)
+
+	return reading, nil
+}
+
+// errorCollector accumulates non-fatal per-item failures so a caller can
+// return partial results instead of aborting on the first error.
+type errorCollector struct {
+	warnings []string
+}
+
+func (e *errorCollector) addf(format string, args ...interface{}) {
+	e.warnings = append(e.warnings, fmt.Sprintf(format, args...))
+}
+
+// stringToUTF16Ptr converts s to a UTF-16 pointer, recording a warning on
+// errs and returning nil on failure rather than propagating the error.
+func stringToUTF16Ptr(s string, logger logging.Logger, errs *errorCollector) *uint16 {
+	p, err := windows.UTF16PtrFromString(s)
+	if err != nil {
+		logger.Debugf("UTF16PtrFromString(%q) failed: %v", s, err)
+		errs.addf("drive %s: %v", s, err)
+		return nil
+	}
+	return p
+}
+
+// listLogicalDrives returns every drive root (e.g. "C:\") currently known
+// to the system via GetLogicalDriveStringsW.
+func listLogicalDrives(logger logging.Logger) ([]string, error) {
+	n, err := windows.GetLogicalDriveStrings(0, nil)
+	if err != nil {
+		logger.Debugf("GetLogicalDriveStrings (size query) failed: %v", err)
+		return nil, err
+	}
+
+	buf := make([]uint16, n)
+	n, err = windows.GetLogicalDriveStrings(uint32(len(buf)), &buf[0])
+	if err != nil {
+		logger.Debugf("GetLogicalDriveStrings failed: %v", err)
+		return nil, err
+	}
+
+	var drives []string
+	for _, s := range splitNullTerminatedUTF16(buf[:n]) {
+		drives = append(drives, s)
+	}
+
+	return drives, nil
+}
+
+// splitNullTerminatedUTF16 splits a double-null-terminated UTF-16 string
+// list (as returned by GetLogicalDriveStringsW) into individual strings.
+func splitNullTerminatedUTF16(buf []uint16) []string {
+	var result []string
+	start := 0
+	for i, c := range buf {
+		if c == 0 {
+			if i > start {
+				result = append(result, windows.UTF16ToString(buf[start:i]))
+			}
+			start = i + 1
+		}
+	}
+	return result
+}
+
+// driveTypeName maps a GetDriveTypeW result to a short, stable string.
+func driveTypeName(driveType uint32) string {
+	switch driveType {
+	case windows.DRIVE_FIXED:
+		return "fixed"
+	case windows.DRIVE_REMOVABLE:
+		return "removable"
+	case windows.DRIVE_REMOTE:
+		return "remote"
+	case windows.DRIVE_CDROM:
+		return "cdrom"
+	case windows.DRIVE_RAMDISK:
+		return "ramdisk"
+	case windows.DRIVE_NO_ROOT_DIR:
+		return "no_root_dir"
+	default:
+		return "unknown"
+	}
+}
+
+// evaluateStatus classifies a drive as "ok", "warn", or "critical" against
+// t, along with a human-readable reason. A zero threshold disables that
+// check.
+func evaluateStatus(usedPercent float64, freeBytes uint64, t thresholds) (string, string) {
+	if t.critUsedPercent > 0 && usedPercent >= t.critUsedPercent {
+		return "critical", fmt.Sprintf("used_percent %.1f%% at or above critical threshold %.1f%%", usedPercent, t.critUsedPercent)
+	}
+	if t.minFreeBytes > 0 && freeBytes <= t.minFreeBytes {
+		return "critical", fmt.Sprintf("free_bytes %d at or below min_free_bytes %d", freeBytes, t.minFreeBytes)
+	}
+	if t.warnUsedPercent > 0 && usedPercent >= t.warnUsedPercent {
+		return "warn", fmt.Sprintf("used_percent %.1f%% at or above warn threshold %.1f%%", usedPercent, t.warnUsedPercent)
+	}
+	return "ok", "within configured thresholds"
+}
+
+// statusSeverity orders statuses so the worst of several drives can be
+// picked with a simple comparison.
+func statusSeverity(status string) int {
+	switch status {
+	case "critical":
+		return 2
+	case "warn":
+		return 1
+	default:
+		return 0
+	}
+}
+
+func (s *windowsDiagnosticsDisk) getThresholds() thresholds {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.thresholds
+}
+
+func (s *windowsDiagnosticsDisk) setThresholds(t thresholds) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.thresholds = t
+}
+
+// recordHistory appends reading to the in-memory ring buffer, evicting the
+// oldest entry once historySize is exceeded.
+func (s *windowsDiagnosticsDisk) recordHistory(reading map[string]interface{}) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.history = append(s.history, reading)
+	if len(s.history) > historySize {
+		s.history = s.history[len(s.history)-historySize:]
+	}
+}
+
+// recentHistory returns (a copy of) the last n recorded readings, oldest
+// first. n <= 0 or n greater than the buffer's length returns everything
+// available.
+func (s *windowsDiagnosticsDisk) recentHistory(n int) []map[string]interface{} {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if n <= 0 || n > len(s.history) {
+		n = len(s.history)
+	}
+
+	out := make([]map[string]interface{}, n)
+	copy(out, s.history[len(s.history)-n:])
+	return out
+}
+
+// commandNumber extracts a float64 from cmd[key], tolerating the
+// float64/int/int64 shapes a caller might pass.
+func commandNumber(cmd map[string]interface{}, key string) (float64, bool) {
+	v, ok := cmd[key]
+	if !ok {
+		return 0, false
+	}
+	switch n := v.(type) {
+	case float64:
+		return n, true
+	case int:
+		return float64(n), true
+	case int64:
+		return float64(n), true
+	default:
+		return 0, false
+	}
 }
 
 func (s *windowsDiagnosticsDisk) DoCommand(
 	ctx context.Context,
 	cmd map[string]interface{},
 ) (map[string]interface{}, error) {
-	return nil, errUnimplemented
+
+	rawCmd, ok := cmd["cmd"].(string)
+	if !ok {
+		return nil, errors.New("windowsdiagnostics: DoCommand requires a string \"cmd\" field")
+	}
+
+	switch rawCmd {
+	case "get_thresholds":
+		t := s.getThresholds()
+		return map[string]interface{}{
+			"warn_used_percent": t.warnUsedPercent,
+			"crit_used_percent": t.critUsedPercent,
+			"min_free_bytes":    t.minFreeBytes,
+		}, nil
+
+	case "set_thresholds":
+		t := s.getThresholds()
+		if v, ok := commandNumber(cmd, "warn_used_percent"); ok {
+			t.warnUsedPercent = v
+		}
+		if v, ok := commandNumber(cmd, "crit_used_percent"); ok {
+			t.critUsedPercent = v
+		}
+		if v, ok := commandNumber(cmd, "min_free_bytes"); ok {
+			if v < 0 {
+				return nil, fmt.Errorf("windowsdiagnostics: min_free_bytes must be >= 0, got %v", v)
+			}
+			t.minFreeBytes = uint64(v)
+		}
+		s.setThresholds(t)
+		return map[string]interface{}{
+			"warn_used_percent": t.warnUsedPercent,
+			"crit_used_percent": t.critUsedPercent,
+			"min_free_bytes":    t.minFreeBytes,
+		}, nil
+
+	case "sample_now":
+		return s.Readings(ctx, nil)
+
+	case "history":
+		n, _ := commandNumber(cmd, "n")
+		history := s.recentHistory(int(n))
+		readings := make([]interface{}, len(history))
+		for i, r := range history {
+			readings[i] = r
+		}
+		return map[string]interface{}{"readings": readings}, nil
+
+	default:
+		return nil, fmt.Errorf("windowsdiagnostics: unknown cmd %q", rawCmd)
+	}
 }
 
 func (s *windowsDiagnosticsDisk) Close(context.Context) error {
@@ -213,3 +640,53 @@ func getDiskUsage(
 
 	return totalNumberOfBytes, totalNumberOfFreeBytes, freeBytesAvailable, nil
 }
+
+// getVolumeInfo calls GetVolumeInformationW for path and returns its label,
+// serial number, filesystem, and decoded filesystem flags. Callers should
+// treat a non-nil error (e.g. ERROR_NOT_READY on an empty removable drive)
+// as "no volume metadata available" rather than fatal.
+func getVolumeInfo(path string, logger logging.Logger) (map[string]interface{}, error) {
+	p, err := windows.UTF16PtrFromString(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var (
+		volumeNameBuf      = make([]uint16, 256)
+		serialNumber       uint32
+		maxComponentLength uint32
+		fsFlags            uint32
+		fsNameBuf          = make([]uint16, 256)
+	)
+
+	logger.Debugf("Calling GetVolumeInformationW(%q)", path)
+
+	err = windows.GetVolumeInformation(
+		p,
+		&volumeNameBuf[0],
+		uint32(len(volumeNameBuf)),
+		&serialNumber,
+		&maxComponentLength,
+		&fsFlags,
+		&fsNameBuf[0],
+		uint32(len(fsNameBuf)),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return map[string]interface{}{
+		"volume_label":          windows.UTF16ToString(volumeNameBuf),
+		"serial_number":         formatVolumeSerial(serialNumber),
+		"filesystem":            windows.UTF16ToString(fsNameBuf),
+		"max_component_length":  maxComponentLength,
+		"read_only":             fsFlags&windows.FILE_READ_ONLY_VOLUME != 0,
+		"compression_supported": fsFlags&windows.FILE_FILE_COMPRESSION != 0,
+	}, nil
+}
+
+// formatVolumeSerial renders a volume serial number as the familiar
+// "XXXX-XXXX" form shown by `dir` and Explorer.
+func formatVolumeSerial(serial uint32) string {
+	return fmt.Sprintf("%04X-%04X", serial>>16, serial&0xFFFF)
+}
@@ -0,0 +1,327 @@
+//go:build windows
+
+package windowsdiagnostics
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"unsafe"
+
+	"go.viam.com/rdk/logging"
+	"golang.org/x/sys/windows"
+)
+
+const (
+	// ioctlVolumeGetVolumeDiskExtents is IOCTL_VOLUME_GET_VOLUME_DISK_EXTENTS.
+	ioctlVolumeGetVolumeDiskExtents = 0x00560000
+
+	// ioctlStoragePredictFailure is IOCTL_STORAGE_PREDICT_FAILURE.
+	ioctlStoragePredictFailure = 0x002D1100
+
+	// ioctlStorageQueryProperty is IOCTL_STORAGE_QUERY_PROPERTY.
+	ioctlStorageQueryProperty = 0x002D1400
+
+	storageDevicePropertyID = 0 // StorageDeviceProperty
+	propertyStandardQuery   = 0 // PropertyStandardQuery
+
+	// maxDiskExtents bounds how many extents we'll read back for a single
+	// (possibly spanned) volume.
+	maxDiskExtents = 16
+)
+
+// diskExtent mirrors Windows' DISK_EXTENT.
+type diskExtent struct {
+	DiskNumber     uint32
+	StartingOffset int64
+	ExtentLength   int64
+}
+
+// volumeDiskExtentsBuffer mirrors VOLUME_DISK_EXTENTS, sized to hold up to
+// maxDiskExtents entries in place of its trailing flexible array.
+type volumeDiskExtentsBuffer struct {
+	NumberOfDiskExtents uint32
+	Extents             [maxDiskExtents]diskExtent
+}
+
+// storagePredictFailure mirrors STORAGE_PREDICT_FAILURE.
+type storagePredictFailure struct {
+	PredictFailure uint32
+	VendorSpecific [512]byte
+}
+
+// storagePropertyQuery mirrors STORAGE_PROPERTY_QUERY.
+type storagePropertyQuery struct {
+	PropertyID           uint32
+	QueryType            uint32
+	AdditionalParameters [1]byte
+}
+
+// storageDeviceDescriptor mirrors the fixed-size portion of
+// STORAGE_DEVICE_DESCRIPTOR; the variable-length identity strings live in
+// the surrounding buffer at the offsets below.
+type storageDeviceDescriptor struct {
+	Version               uint32
+	Size                  uint32
+	DeviceType            byte
+	DeviceTypeModifier    byte
+	RemovableMedia        byte
+	CommandQueueing       byte
+	VendorIDOffset        uint32
+	ProductIDOffset       uint32
+	ProductRevisionOffset uint32
+	SerialNumberOffset    uint32
+	BusType               uint32
+	RawPropertiesLength   uint32
+}
+
+// querySmartInfo maps rootPath to its underlying physical disk(s) and
+// returns predict-failure and device-identity info for each. A volume can
+// span multiple disks (e.g. a mirrored Storage Spaces volume); a failure
+// on one disk (commonly ERROR_ACCESS_DENIED, since most of these ioctls
+// require Administrator) is recorded as a warning rather than discarding
+// the disks that did succeed. The returned error is non-nil only when
+// rootPath's disk(s) couldn't be determined at all.
+func querySmartInfo(rootPath string, logger logging.Logger) ([]map[string]interface{}, []string, error) {
+	diskNumbers, err := volumeDiskNumbers(rootPath, logger)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	errs := &errorCollector{}
+	disks := make([]map[string]interface{}, 0, len(diskNumbers))
+
+	for _, diskNumber := range diskNumbers {
+		physicalPath := fmt.Sprintf(`\\.\PhysicalDrive%d`, diskNumber)
+
+		predictFailure, err := queryPredictFailure(physicalPath, logger)
+		if err != nil {
+			errs.addf("disk %d: %s", diskNumber, smartWarning(physicalPath, err))
+			continue
+		}
+
+		descriptor, err := queryStorageDeviceDescriptor(physicalPath, logger)
+		if err != nil {
+			errs.addf("disk %d: %s", diskNumber, smartWarning(physicalPath, err))
+			continue
+		}
+
+		disk := map[string]interface{}{
+			"disk_number":     diskNumber,
+			"predict_failure": predictFailure,
+		}
+		for k, v := range descriptor {
+			disk[k] = v
+		}
+
+		disks = append(disks, disk)
+	}
+
+	return disks, errs.warnings, nil
+}
+
+// smartWarning renders a SMART query failure as a human-readable string,
+// calling out the common Administrator-required case.
+func smartWarning(path string, err error) string {
+	if errors.Is(err, windows.ERROR_ACCESS_DENIED) {
+		return fmt.Sprintf("SMART query for %s requires Administrator privileges", path)
+	}
+	return fmt.Sprintf("SMART query for %s failed: %v", path, err)
+}
+
+// volumeDevicePath turns a root path like `C:\` into the device path
+// `\\.\C:` expected by the volume ioctls.
+func volumeDevicePath(rootPath string) string {
+	return `\\.\` + strings.TrimRight(rootPath, `\`)
+}
+
+// volumeDiskNumbers returns the distinct physical disk numbers backing
+// rootPath, following IOCTL_VOLUME_GET_VOLUME_DISK_EXTENTS so spanned
+// volumes report every extent's disk.
+func volumeDiskNumbers(rootPath string, logger logging.Logger) ([]uint32, error) {
+	handle, err := openDeviceHandle(volumeDevicePath(rootPath), logger)
+	if err != nil {
+		return nil, err
+	}
+	defer windows.CloseHandle(handle)
+
+	var buf volumeDiskExtentsBuffer
+	var bytesReturned uint32
+
+	err = windows.DeviceIoControl(
+		handle,
+		ioctlVolumeGetVolumeDiskExtents,
+		nil,
+		0,
+		(*byte)(unsafe.Pointer(&buf)),
+		uint32(unsafe.Sizeof(buf)),
+		&bytesReturned,
+		nil,
+	)
+	if err != nil {
+		logger.Debugf("DeviceIoControl(IOCTL_VOLUME_GET_VOLUME_DISK_EXTENTS, %q) failed: %v", rootPath, err)
+		return nil, err
+	}
+
+	n := buf.NumberOfDiskExtents
+	if n > maxDiskExtents {
+		n = maxDiskExtents
+	}
+
+	var numbers []uint32
+	seen := map[uint32]bool{}
+	for i := uint32(0); i < n; i++ {
+		dn := buf.Extents[i].DiskNumber
+		if !seen[dn] {
+			seen[dn] = true
+			numbers = append(numbers, dn)
+		}
+	}
+
+	return numbers, nil
+}
+
+// queryPredictFailure issues IOCTL_STORAGE_PREDICT_FAILURE against
+// physicalPath.
+func queryPredictFailure(physicalPath string, logger logging.Logger) (bool, error) {
+	handle, err := openDeviceHandle(physicalPath, logger)
+	if err != nil {
+		return false, err
+	}
+	defer windows.CloseHandle(handle)
+
+	var result storagePredictFailure
+	var bytesReturned uint32
+
+	err = windows.DeviceIoControl(
+		handle,
+		ioctlStoragePredictFailure,
+		nil,
+		0,
+		(*byte)(unsafe.Pointer(&result)),
+		uint32(unsafe.Sizeof(result)),
+		&bytesReturned,
+		nil,
+	)
+	if err != nil {
+		logger.Debugf("DeviceIoControl(IOCTL_STORAGE_PREDICT_FAILURE, %q) failed: %v", physicalPath, err)
+		return false, err
+	}
+
+	return result.PredictFailure != 0, nil
+}
+
+// queryStorageDeviceDescriptor issues IOCTL_STORAGE_QUERY_PROPERTY for
+// StorageDeviceProperty against physicalPath and decodes the device's
+// vendor/product identity and bus type.
+func queryStorageDeviceDescriptor(physicalPath string, logger logging.Logger) (map[string]interface{}, error) {
+	handle, err := openDeviceHandle(physicalPath, logger)
+	if err != nil {
+		return nil, err
+	}
+	defer windows.CloseHandle(handle)
+
+	query := storagePropertyQuery{
+		PropertyID: storageDevicePropertyID,
+		QueryType:  propertyStandardQuery,
+	}
+
+	buf := make([]byte, 1024)
+	var bytesReturned uint32
+
+	err = windows.DeviceIoControl(
+		handle,
+		ioctlStorageQueryProperty,
+		(*byte)(unsafe.Pointer(&query)),
+		uint32(unsafe.Sizeof(query)),
+		&buf[0],
+		uint32(len(buf)),
+		&bytesReturned,
+		nil,
+	)
+	if err != nil {
+		logger.Debugf("DeviceIoControl(IOCTL_STORAGE_QUERY_PROPERTY, %q) failed: %v", physicalPath, err)
+		return nil, err
+	}
+
+	desc := (*storageDeviceDescriptor)(unsafe.Pointer(&buf[0]))
+
+	return map[string]interface{}{
+		"vendor_id":       cStringAtOffset(buf, desc.VendorIDOffset),
+		"product_id":      cStringAtOffset(buf, desc.ProductIDOffset),
+		"serial_number":   cStringAtOffset(buf, desc.SerialNumberOffset),
+		"bus_type":        busTypeName(desc.BusType),
+		"removable_media": desc.RemovableMedia != 0,
+	}, nil
+}
+
+// cStringAtOffset reads a NUL-terminated string out of buf starting at
+// offset. An offset of 0 (or out of range) means the field isn't present.
+func cStringAtOffset(buf []byte, offset uint32) string {
+	if offset == 0 || offset >= uint32(len(buf)) {
+		return ""
+	}
+
+	end := offset
+	for end < uint32(len(buf)) && buf[end] != 0 {
+		end++
+	}
+
+	return string(buf[offset:end])
+}
+
+// busTypeName maps a STORAGE_BUS_TYPE value to its well-known name.
+func busTypeName(busType uint32) string {
+	switch busType {
+	case 0x1:
+		return "scsi"
+	case 0x2:
+		return "atapi"
+	case 0x3:
+		return "ata"
+	case 0x6:
+		return "fibre"
+	case 0x7:
+		return "usb"
+	case 0x9:
+		return "iscsi"
+	case 0xA:
+		return "sas"
+	case 0xB:
+		return "sata"
+	case 0xC:
+		return "sd"
+	case 0xD:
+		return "mmc"
+	case 0xE:
+		return "virtual"
+	case 0x10:
+		return "spaces"
+	case 0x11:
+		return "nvme"
+	default:
+		return fmt.Sprintf("unknown(0x%X)", busType)
+	}
+}
+
+// openDeviceHandle opens path (a physical drive or volume device path) for
+// read access, sharing read and write with other handles, as the storage
+// ioctls need.
+func openDeviceHandle(path string, logger logging.Logger) (windows.Handle, error) {
+	p, err := windows.UTF16PtrFromString(path)
+	if err != nil {
+		return 0, err
+	}
+
+	logger.Debugf("Opening %q for storage query", path)
+
+	return windows.CreateFile(
+		p,
+		windows.GENERIC_READ,
+		windows.FILE_SHARE_READ|windows.FILE_SHARE_WRITE,
+		nil,
+		windows.OPEN_EXISTING,
+		0,
+		0,
+	)
+}